@@ -20,29 +20,25 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/klog"
 )
 
+// defaultOptions backs the package-level FlagSet. It is bound through the
+// same Options.BindFlags used by programmatic callers, so the legacy
+// FlagSet() and the Options API can never drift out of sync with one
+// another as new flags are added.
 var (
-	zapFlagSet *pflag.FlagSet
-
-	development   bool
-	encoderVal    encoderValue
-	levelVal      levelValue
-	sampleVal     sampleValue
-	timeformatVal timeformatValue
+	zapFlagSet     *pflag.FlagSet
+	defaultOptions = &Options{}
 )
 
 func init() {
 	zapFlagSet = pflag.NewFlagSet("zap", pflag.ExitOnError)
-	zapFlagSet.BoolVar(&development, "zap-devel", false, "Enable zap development mode (changes defaults to console encoder, debug log level, and disables sampling)")
-	zapFlagSet.Var(&encoderVal, "zap-encoder", "Zap log encoding ('json' or 'console')")
-	zapFlagSet.Var(&levelVal, "zap-level", "Zap log level (one of 'debug', 'info', 'error' or any integer value > 0)")
-	zapFlagSet.Var(&sampleVal, "zap-sample", "Enable zap log sampling. Sampling will be disabled for integer log levels > 1")
-	zapFlagSet.Var(&timeformatVal, "zap-timeformat", "Use 'unix' or 'iso8601' time formatting. 'unix' is the default.")
+	defaultOptions.BindFlags(zapFlagSet)
 }
 
 // FlagSet - The zap logging flagset.
@@ -50,50 +46,37 @@ func FlagSet() *pflag.FlagSet {
 	return zapFlagSet
 }
 
-type encoderValue struct {
-	set     bool
-	encoder zapcore.Encoder
-	str     string
-}
-
-func (v *encoderValue) Set(e string) error {
-	v.set = true
-	switch e {
-	case "json":
-		v.encoder = jsonEncoder()
-	case "console":
-		v.encoder = consoleEncoder()
-	default:
-		return fmt.Errorf("unknown encoder \"%s\"", e)
-	}
-	v.str = e
-	return nil
-}
-
-func (v encoderValue) String() string {
-	return v.str
-}
-
-func (v encoderValue) Type() string {
-	return "encoder"
+// LoggerFromFlags returns a new logr.Logger backed by zap, configured from
+// the flags registered on FlagSet() (parse FlagSet() before calling this).
+// The returned flush function must be called before the process exits to
+// ensure buffered entries are written out.
+func LoggerFromFlags() (logr.Logger, func() error) {
+	sink, flush := newOutputSink(*defaultOptions)
+	return NewLoggerTo(sink, *defaultOptions), flush
 }
 
-func jsonEncoder() zapcore.Encoder {
+func jsonEncoder(opts ...EncoderConfigOption) zapcore.Encoder {
 	encoderConfig := zap.NewProductionEncoderConfig()
-	if timeformatVal.String() == "iso8601" {
-		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeTime = zapcore.EpochTimeEncoder
+	for _, opt := range opts {
+		opt(&encoderConfig)
 	}
 	return zapcore.NewJSONEncoder(encoderConfig)
 }
 
-func consoleEncoder() zapcore.Encoder {
+func consoleEncoder(opts ...EncoderConfigOption) zapcore.Encoder {
 	encoderConfig := zap.NewDevelopmentEncoderConfig()
-	if timeformatVal.String() == "iso8601" {
-		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeTime = zapcore.EpochTimeEncoder
+	for _, opt := range opts {
+		opt(&encoderConfig)
 	}
 	return zapcore.NewConsoleEncoder(encoderConfig)
 }
 
+// levelValue is the canonical parser for the "zap-level" flag value. It is
+// shared by levelFlag so that the package FlagSet and Options.BindFlags stay
+// behaviorally identical, including the klog/glog verbosity side effect
+// below, instead of maintaining two copies of the same switch statement.
 type levelValue struct {
 	set   bool
 	level zapcore.Level
@@ -143,57 +126,42 @@ func (v levelValue) Type() string {
 	return "level"
 }
 
-type sampleValue struct {
-	set    bool
-	sample bool
-}
-
-func (v *sampleValue) Set(s string) error {
-	var err error
-	v.set = true
-	v.sample, err = strconv.ParseBool(s)
-	return err
-}
-
-func (v sampleValue) String() string {
-	return strconv.FormatBool(v.sample)
-}
-
-func (v sampleValue) IsBoolFlag() bool {
-	return true
-}
-
-func (v sampleValue) Type() string {
-	return "sample"
-}
-
-type timeformatValue struct {
-	set bool
-	str string
+// timeEncoderValue is the canonical parser for the "zap-time-encoding" flag
+// value. It is shared by timeEncoderFlag (and, through its deprecated-alias
+// mapping, by timeformatFlag) so the package FlagSet and Options.BindFlags
+// stay behaviorally identical.
+type timeEncoderValue struct {
+	set     bool
+	encoder zapcore.TimeEncoder
+	str     string
 }
 
-func (v *timeformatValue) Set(s string) error {
+func (v *timeEncoderValue) Set(s string) error {
 	v.set = true
-	if len(s) > 1 {
-		if s == "unix" || s == "iso8601" {
-			v.str = s
-			return nil
-		}
-		return fmt.Errorf("unknown timeformat \"%s\"", s)
-
+	switch s {
+	case "epoch":
+		v.encoder = zapcore.EpochTimeEncoder
+	case "millis":
+		v.encoder = zapcore.EpochMillisTimeEncoder
+	case "nanos":
+		v.encoder = zapcore.EpochNanosTimeEncoder
+	case "iso8601":
+		v.encoder = zapcore.ISO8601TimeEncoder
+	case "rfc3339":
+		v.encoder = zapcore.RFC3339TimeEncoder
+	case "rfc3339nano":
+		v.encoder = zapcore.RFC3339NanoTimeEncoder
+	default:
+		return fmt.Errorf("unknown time encoding \"%s\"", s)
 	}
-	v.str = "unix"
+	v.str = s
 	return nil
 }
 
-func (v timeformatValue) String() string {
+func (v timeEncoderValue) String() string {
 	return v.str
 }
 
-func (v timeformatValue) IsBoolFlag() bool {
-	return false
-}
-
-func (v timeformatValue) Type() string {
-	return "string"
+func (v timeEncoderValue) Type() string {
+	return "timeEncoder"
 }
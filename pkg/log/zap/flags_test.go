@@ -0,0 +1,101 @@
+// Copyright 2019 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// encodeTimeForTest renders ts through enc via a throwaway JSON encoder, so
+// two zapcore.TimeEncoder values (which aren't comparable) can be compared
+// by their output instead.
+func encodeTimeForTest(t *testing.T, enc zapcore.TimeEncoder, ts time.Time) string {
+	t.Helper()
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = enc
+	buf, err := zapcore.NewJSONEncoder(cfg).EncodeEntry(zapcore.Entry{Time: ts, Level: zapcore.InfoLevel, Message: "m"}, nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+	return buf.String()
+}
+
+func TestTimeEncoderValueSet(t *testing.T) {
+	for _, tc := range []string{"epoch", "millis", "nanos", "iso8601", "rfc3339", "rfc3339nano"} {
+		var v timeEncoderValue
+		if err := v.Set(tc); err != nil {
+			t.Errorf("Set(%q) returned error: %v", tc, err)
+			continue
+		}
+		if v.encoder == nil {
+			t.Errorf("Set(%q) left encoder nil", tc)
+		}
+		if v.String() != tc {
+			t.Errorf("String() = %q, want %q", v.String(), tc)
+		}
+	}
+
+	var v timeEncoderValue
+	if err := v.Set("bogus"); err == nil {
+		t.Error("Set(\"bogus\") expected an error, got nil")
+	}
+}
+
+func TestZapTimeformatDeprecatedAlias(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	wantEpoch := encodeTimeForTest(t, zapcore.EpochTimeEncoder, ts)
+	wantISO8601 := encodeTimeForTest(t, zapcore.ISO8601TimeEncoder, ts)
+
+	for in, want := range map[string]string{"unix": wantEpoch, "iso8601": wantISO8601} {
+		o := &Options{}
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		o.BindFlags(fs)
+
+		if err := fs.Parse([]string{"--zap-timeformat=" + in}); err != nil {
+			t.Fatalf("Parse(--zap-timeformat=%s): %v", in, err)
+		}
+		if got := encodeTimeForTest(t, o.TimeEncoder, ts); got != want {
+			t.Errorf("--zap-timeformat=%s: got %q, want %q", in, got, want)
+		}
+	}
+
+	o := &Options{}
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	o.BindFlags(fs)
+	if err := fs.Parse([]string{"--zap-timeformat=bogus"}); err == nil {
+		t.Error("Parse(--zap-timeformat=bogus) expected an error, got nil")
+	}
+}
+
+func TestLoggerFromFlagsConsumesFlagSet(t *testing.T) {
+	if err := FlagSet().Parse([]string{"--zap-level=error"}); err != nil {
+		t.Fatalf("Parse(--zap-level=error): %v", err)
+	}
+
+	if got := defaultOptions.Level; got != zapcore.ErrorLevel {
+		t.Fatalf("defaultOptions.Level = %v, want %v", got, zapcore.ErrorLevel)
+	}
+
+	log, flush := LoggerFromFlags()
+	defer flush()
+	if log.GetSink() == nil {
+		t.Error("LoggerFromFlags() returned a logr.Logger with a nil sink")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zap
+
+import (
+	"os"
+
+	"go.uber.org/zap/zapcore"
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// outputFile returns the standard stream selected by output ("" and
+// "stderr" both mean os.Stderr, "stdout" means os.Stdout), or nil when
+// output names a file path instead.
+func outputFile(output string) *os.File {
+	switch output {
+	case "", "stderr":
+		return os.Stderr
+	case "stdout":
+		return os.Stdout
+	default:
+		return nil
+	}
+}
+
+// newOutputSink builds the zapcore.WriteSyncer selected by o.Output, locked
+// for concurrent use, along with a flush function that must be called before
+// the process exits. File paths are routed through lumberjack so they are
+// rotated according to o.MaxSize, o.MaxBackups, o.MaxAge and o.Compress.
+func newOutputSink(o Options) (zapcore.WriteSyncer, func() error) {
+	if f := outputFile(o.Output); f != nil {
+		return zapcore.Lock(f), func() error { return nil }
+	}
+
+	lj := &lumberjack.Logger{
+		Filename:   o.Output,
+		MaxSize:    o.MaxSize,
+		MaxBackups: o.MaxBackups,
+		MaxAge:     o.MaxAge,
+		Compress:   o.Compress,
+	}
+	return zapcore.AddSync(lj), lj.Close
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewOutputSinkStdStreams(t *testing.T) {
+	for _, output := range []string{"", "stderr", "stdout"} {
+		sink, flush := newOutputSink(Options{Output: output})
+		if sink == nil {
+			t.Errorf("Output %q: sink is nil", output)
+			continue
+		}
+		if err := flush(); err != nil {
+			t.Errorf("Output %q: flush() returned error: %v", output, err)
+		}
+	}
+}
+
+func TestNewOutputSinkFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operator.log")
+
+	sink, flush := newOutputSink(Options{Output: path, MaxSize: 1})
+
+	if _, err := sink.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := flush(); err != nil {
+		t.Fatalf("flush(): %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), "hello") {
+		t.Errorf("log file contents = %q, want it to contain %q", data, "hello")
+	}
+}
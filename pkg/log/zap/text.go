@@ -0,0 +1,84 @@
+// Copyright 2019 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zap
+
+import (
+	"os"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// textEncoder builds the human-friendly "text" encoder used for interactive
+// development (e.g. `operator-sdk run --local`): a colored capital level
+// (only when output is actually a terminal), a compact local time (unless
+// overridden by an EncoderConfigOption, e.g. from
+// --zap-time-encoding/--zap-timeformat), short caller locations, and
+// durations rendered with k8s.io/apimachinery's HumanDuration instead of
+// zap's raw "1.234s". output is the same "stderr"/"stdout"/file-path value
+// as Options.Output, so color is gated on where the bytes actually land
+// rather than on stderr regardless of the real destination.
+func textEncoder(output string, opts ...EncoderConfigOption) zapcore.Encoder {
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.EncodeTime = compactTimeEncoder
+	encoderConfig.EncodeDuration = humanDurationEncoder
+	encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+	if supportsColor(output) {
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	} else {
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+	for _, opt := range opts {
+		opt(&encoderConfig)
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
+
+// compactTimeEncoder formats timestamps as "15:04:05.000", dropping the date
+// that the default encoders always include.
+func compactTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(t.Format("15:04:05.000"))
+}
+
+// humanDurationEncoder renders time.Duration fields the way kubectl does,
+// e.g. "4h3m", instead of the "4h3m0s" of zap's development encoder config.
+func humanDurationEncoder(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(duration.HumanDuration(d))
+}
+
+// supportsColor reports whether colored level output can be used for the
+// given output destination: a non-nil standard stream (stdout/stderr) that
+// is a terminal, and Windows terminals are excluded since they historically
+// don't interpret ANSI color codes. File destinations never support color,
+// since colored output would leave ANSI escape codes in the persisted file.
+func supportsColor(output string) bool {
+	if runtime.GOOS == "windows" {
+		return false
+	}
+	return isTerminal(outputFile(output))
+}
+
+// isTerminal reports whether f is a terminal. A nil f (e.g. a file
+// destination, which outputFile returns nil for) is never a terminal.
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zap
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCompactTimeEncoder(t *testing.T) {
+	cfg := zap.NewDevelopmentEncoderConfig()
+	cfg.EncodeTime = compactTimeEncoder
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf, err := zapcore.NewConsoleEncoder(cfg).EncodeEntry(zapcore.Entry{Time: ts, Level: zapcore.InfoLevel, Message: "hi"}, nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+	if !strings.Contains(buf.String(), "03:04:05.000") {
+		t.Errorf("output = %q, want it to contain %q", buf.String(), "03:04:05.000")
+	}
+}
+
+func TestHumanDurationEncoder(t *testing.T) {
+	cfg := zap.NewDevelopmentEncoderConfig()
+	cfg.EncodeDuration = humanDurationEncoder
+
+	fields := []zapcore.Field{zap.Duration("elapsed", 90*time.Minute)}
+	buf, err := zapcore.NewConsoleEncoder(cfg).EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Message: "hi"}, fields)
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+	if !strings.Contains(buf.String(), "90m") {
+		t.Errorf("output = %q, want it to contain %q", buf.String(), "90m")
+	}
+	if strings.Contains(buf.String(), "1h30m0s") {
+		t.Errorf("output = %q, still contains zap's raw duration format", buf.String())
+	}
+}
+
+func TestSupportsColorFileNeverColored(t *testing.T) {
+	// A file destination should never support color, regardless of whether
+	// the calling process's stderr happens to be a terminal: otherwise ANSI
+	// escape codes could end up persisted in the log file.
+	path := filepath.Join(t.TempDir(), "operator.log")
+	if got := supportsColor(path); got {
+		t.Errorf("supportsColor(%q) = true, want false", path)
+	}
+}
+
+func TestIsTerminalPipe(t *testing.T) {
+	// os.Pipe() endpoints are guaranteed to never be terminals, giving a
+	// deterministic non-TTY fixture independent of how the test is invoked.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if got := isTerminal(w); got {
+		t.Errorf("isTerminal(pipe) = true, want false")
+	}
+}
+
+func TestIsTerminalNilFile(t *testing.T) {
+	if got := isTerminal(nil); got {
+		t.Errorf("isTerminal(nil) = true, want false")
+	}
+}
+
+func TestSupportsColorWindowsDisabled(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("this assertion only applies on windows, where color is always disabled")
+	}
+	if got := supportsColor("stdout"); got {
+		t.Errorf("supportsColor(\"stdout\") = true, want false on windows")
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2019 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// EncoderConfigOption can be used to modify the zapcore.EncoderConfig used to
+// build an Options' Encoder.
+type EncoderConfigOption func(*zapcore.EncoderConfig)
+
+// Options contains all of the options for constructing a zap-backed
+// logr.Logger programmatically, as an alternative to the package-level
+// FlagSet. Zero-value fields are filled in with the package defaults.
+type Options struct {
+	// Development configures the logger to use development defaults: console
+	// encoder, debug log level, and no sampling.
+	Development bool
+
+	// Encoder is used to format the log entries. If nil, one is built from
+	// Development, TimeEncoder and EncoderConfigOptions.
+	Encoder zapcore.Encoder
+
+	// Level is the minimum enabled logging level. Defaults to zap.InfoLevel
+	// (zap.DebugLevel in Development).
+	Level zapcore.LevelEnabler
+
+	// StacktraceLevel is the level at and above which logged entries include
+	// a stack trace. Defaults to zap.ErrorLevel (zap.WarnLevel in
+	// Development).
+	StacktraceLevel zapcore.LevelEnabler
+
+	// TimeEncoder configures how log entry timestamps are formatted. Only
+	// consulted when Encoder is nil.
+	TimeEncoder zapcore.TimeEncoder
+
+	// Sample enables log sampling. Defaults to true outside of Development.
+	Sample *bool
+
+	// EncoderConfigOptions are applied, in order, to the zapcore.EncoderConfig
+	// used to build Encoder when Encoder is nil.
+	EncoderConfigOptions []EncoderConfigOption
+
+	// ZapOpts are passed through to zap.New when building the logger.
+	ZapOpts []zap.Option
+
+	// Output selects where Logger writes log entries: "stderr" (default),
+	// "stdout", or a file path. File paths are rotated via lumberjack using
+	// MaxSize, MaxBackups, MaxAge and Compress.
+	Output string
+
+	// MaxSize is the maximum size in megabytes of a log file before it gets
+	// rotated. Only consulted when Output is a file path. Defaults to 100.
+	MaxSize int
+
+	// MaxBackups is the maximum number of rotated log files to retain. Only
+	// consulted when Output is a file path.
+	MaxBackups int
+
+	// MaxAge is the maximum number of days to retain rotated log files. Only
+	// consulted when Output is a file path.
+	MaxAge int
+
+	// Compress enables gzip compression of rotated log files. Only consulted
+	// when Output is a file path.
+	Compress bool
+
+	// encoderKind records the "json"/"console"/"text" choice made via BindFlags,
+	// deferred until addDefaults so it can still pick up TimeEncoder and
+	// EncoderConfigOptions set on the struct after flag parsing.
+	encoderKind string
+}
+
+// Option configures an Options for use with Logger.
+type Option func(*Options)
+
+// BindFlags registers every flag understood by Options - "zap-devel",
+// "zap-encoder", "zap-level", "zap-stacktrace-level", "zap-time-encoding",
+// its deprecated "zap-timeformat" alias, "zap-output" and its log-rotation
+// companions, and "zap-sample" - against fs, populating the receiver as they
+// are parsed. The package-level FlagSet() is itself just this method bound
+// to a default Options, so the two never expose a different set of flags.
+func (o *Options) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Development, "zap-devel", o.Development,
+		"Enable zap development mode (changes defaults to console encoder, debug log level, and disables sampling)")
+
+	fs.Var(&encoderFlag{o}, "zap-encoder", "Zap log encoding ('json', 'console' or 'text')")
+	fs.Var(&levelFlag{o}, "zap-level", "Zap log level (one of 'debug', 'info', 'error' or any integer value > 0)")
+	fs.Var(&stacktraceLevelFlag{o}, "zap-stacktrace-level",
+		"Zap level at and above which stacktraces are captured (one of 'info', 'error' or 'panic')")
+	fs.Var(&timeEncoderFlag{o}, "zap-time-encoding",
+		"Sets the zap time format ('epoch', 'millis', 'nanos', 'iso8601', 'rfc3339' or 'rfc3339nano'). Defaults to 'epoch' ('15:04:05.000' for the 'text' encoder).")
+	fs.Var(&timeformatFlag{o}, "zap-timeformat",
+		"Use 'unix' or 'iso8601' time formatting. 'unix' is the default ('15:04:05.000' for the 'text' encoder). Deprecated: use zap-time-encoding instead.")
+
+	fs.StringVar(&o.Output, "zap-output", "stderr", "Log sink: 'stderr', 'stdout', or a file path")
+	fs.IntVar(&o.MaxSize, "zap-log-max-size", 100, "Maximum size in megabytes of a log file before it is rotated (file output only)")
+	fs.IntVar(&o.MaxBackups, "zap-log-max-backups", 0, "Maximum number of rotated log files to retain, 0 means retain all (file output only)")
+	fs.IntVar(&o.MaxAge, "zap-log-max-age", 0, "Maximum number of days to retain rotated log files, 0 means retain forever (file output only)")
+	fs.BoolVar(&o.Compress, "zap-log-compress", false, "Compress rotated log files with gzip (file output only)")
+
+	var sample bool
+	fs.BoolVar(&sample, "zap-sample", true, "Enable zap log sampling. Sampling will be disabled for integer log levels > 1")
+	o.Sample = &sample
+}
+
+// addDefaults fills in any unset Options fields with the package defaults.
+func (o *Options) addDefaults() {
+	if o.Level == nil {
+		if o.Development {
+			o.Level = zapcore.DebugLevel
+		} else {
+			o.Level = zapcore.InfoLevel
+		}
+	}
+
+	if o.StacktraceLevel == nil {
+		if o.Development {
+			o.StacktraceLevel = zapcore.WarnLevel
+		} else {
+			o.StacktraceLevel = zapcore.ErrorLevel
+		}
+	}
+	o.ZapOpts = append(o.ZapOpts, zap.AddStacktrace(o.StacktraceLevel))
+
+	if o.Encoder == nil {
+		var configOpts []EncoderConfigOption
+		if o.TimeEncoder != nil {
+			timeEncoder := o.TimeEncoder
+			configOpts = append(configOpts, func(ec *zapcore.EncoderConfig) { ec.EncodeTime = timeEncoder })
+		}
+		configOpts = append(configOpts, o.EncoderConfigOptions...)
+
+		kind := o.encoderKind
+		if kind == "" {
+			if o.Development {
+				kind = "console"
+			} else {
+				kind = "json"
+			}
+		}
+		switch kind {
+		case "console":
+			o.Encoder = consoleEncoder(configOpts...)
+		case "text":
+			o.Encoder = textEncoder(o.Output, configOpts...)
+		default:
+			o.Encoder = jsonEncoder(configOpts...)
+		}
+	}
+
+	if o.Sample == nil {
+		sample := !o.Development
+		o.Sample = &sample
+	}
+	// Sampling is skipped for levels below debug (integer log levels > 1).
+	if *o.Sample && !o.Development && !o.Level.Enabled(zapcore.DebugLevel-1) {
+		o.ZapOpts = append(o.ZapOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Second, 100, 100)
+		}))
+	}
+}
+
+// NewLoggerTo builds a logr.Logger backed by zap, writing encoded entries to
+// destWriter according to opts.
+func NewLoggerTo(destWriter io.Writer, opts Options) logr.Logger {
+	opts.addDefaults()
+
+	sink := zapcore.AddSync(destWriter)
+	core := zapcore.NewCore(opts.Encoder, sink, opts.Level)
+
+	log := zap.New(core)
+	log = log.WithOptions(opts.ZapOpts...)
+	return zapr.NewLogger(log)
+}
+
+// Logger returns a new logr.Logger backed by zap, configured by opts and
+// writing to the sink selected by Options.Output (os.Stderr if unset). The
+// returned flush function must be called before the process exits to ensure
+// buffered entries are written out.
+func Logger(opts ...Option) (logr.Logger, func() error) {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	sink, flush := newOutputSink(*o)
+	return NewLoggerTo(sink, *o), flush
+}
+
+// encoderFlag records the "zap-encoder" flag's choice of encoder kind on
+// Options, deferring actual construction to addDefaults.
+type encoderFlag struct{ o *Options }
+
+func (f *encoderFlag) Set(s string) error {
+	switch s {
+	case "json", "console", "text":
+		f.o.encoderKind = s
+	default:
+		return fmt.Errorf("unknown encoder \"%s\"", s)
+	}
+	return nil
+}
+
+func (f *encoderFlag) String() string {
+	if f.o == nil {
+		return ""
+	}
+	return f.o.encoderKind
+}
+func (f *encoderFlag) Type() string { return "encoder" }
+
+// levelFlag sets Options.Level from the "zap-level" flag, delegating to
+// levelValue so it picks up the klog/glog verbosity side effect rather than
+// re-deriving the level parsing switch.
+type levelFlag struct{ o *Options }
+
+func (f *levelFlag) Set(s string) error {
+	var lv levelValue
+	if err := lv.Set(s); err != nil {
+		return err
+	}
+	f.o.Level = lv.level
+	return nil
+}
+
+func (f *levelFlag) String() string { return "" }
+func (f *levelFlag) Type() string   { return "level" }
+
+// stacktraceLevelFlag sets Options.StacktraceLevel from the
+// "zap-stacktrace-level" flag.
+type stacktraceLevelFlag struct{ o *Options }
+
+func (f *stacktraceLevelFlag) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "info":
+		f.o.StacktraceLevel = zapcore.InfoLevel
+	case "error":
+		f.o.StacktraceLevel = zapcore.ErrorLevel
+	case "panic":
+		f.o.StacktraceLevel = zapcore.PanicLevel
+	default:
+		return fmt.Errorf("invalid stacktrace level \"%s\"", s)
+	}
+	return nil
+}
+
+func (f *stacktraceLevelFlag) String() string { return "" }
+func (f *stacktraceLevelFlag) Type() string   { return "level" }
+
+// timeEncoderFlag sets Options.TimeEncoder from the "zap-time-encoding" flag.
+type timeEncoderFlag struct{ o *Options }
+
+func (f *timeEncoderFlag) Set(s string) error {
+	var te timeEncoderValue
+	if err := te.Set(s); err != nil {
+		return err
+	}
+	f.o.TimeEncoder = te.encoder
+	return nil
+}
+
+func (f *timeEncoderFlag) String() string { return "" }
+func (f *timeEncoderFlag) Type() string   { return "timeEncoder" }
+
+// timeformatFlag sets Options.TimeEncoder from the deprecated "zap-timeformat"
+// flag, mapping its legacy 'unix'/'iso8601' values onto the timeEncoderFlag
+// encoders they correspond to.
+type timeformatFlag struct{ o *Options }
+
+func (f *timeformatFlag) Set(s string) error {
+	switch s {
+	case "unix":
+		return (&timeEncoderFlag{f.o}).Set("epoch")
+	case "iso8601":
+		return (&timeEncoderFlag{f.o}).Set("iso8601")
+	default:
+		return fmt.Errorf("unknown timeformat \"%s\"", s)
+	}
+}
+
+func (f *timeformatFlag) String() string { return "" }
+func (f *timeformatFlag) Type() string   { return "string" }